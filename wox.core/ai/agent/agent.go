@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"wox/ai"
+	"wox/common"
+)
+
+// defaultMaxIterations bounds the tool-execution loop so a model that keeps asking for tools
+// can't run Run forever.
+const defaultMaxIterations = 8
+
+// ConfirmToolCall is called before a tool invocation runs so the UI can ask the user to
+// approve potentially destructive actions. A nil hook approves every call.
+type ConfirmToolCall func(call ai.ToolCall) bool
+
+// ToolExecutor invokes an MCP tool by name with its already-assembled JSON arguments and
+// returns the tool's result text.
+type ToolExecutor interface {
+	Execute(ctx context.Context, tool common.MCPTool, argumentsJSON string) (string, error)
+}
+
+// Toolbox is the curated subset of MCP tools a given Agent is allowed to call.
+type Toolbox struct {
+	Tools    []common.MCPTool
+	Executor ToolExecutor
+}
+
+func (t Toolbox) find(name string) (common.MCPTool, bool) {
+	for _, tool := range t.Tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return common.MCPTool{}, false
+}
+
+// Agent owns a system prompt and a curated Toolbox, and drives the provider/tool-execution
+// loop on top of a Provider until the model produces a plain-text answer.
+type Agent struct {
+	SystemPrompt    string
+	Toolbox         Toolbox
+	Provider        ai.Provider
+	Model           common.Model
+	MaxIterations   int
+	ConfirmToolCall ConfirmToolCall
+}
+
+// Run drives conversation through Provider.ChatStream, executing any tool calls the model
+// makes and feeding the results back as ConversationRoleTool turns, until the model emits a
+// plain-text finish or MaxIterations is reached. It returns the final assistant text and the
+// full conversation, including the tool turns, so callers can persist it.
+func (a *Agent) Run(ctx context.Context, conversation []common.Conversation) (string, []common.Conversation, error) {
+	maxIterations := a.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultMaxIterations
+	}
+
+	conv := append([]common.Conversation{}, conversation...)
+	if a.SystemPrompt != "" {
+		conv = append([]common.Conversation{{Role: common.ConversationRoleSystem, Text: a.SystemPrompt}}, conv...)
+	}
+
+	options := common.ChatOptions{Tools: a.Toolbox.Tools}
+
+	for i := 0; i < maxIterations; i++ {
+		text, toolCalls, err := a.runOnce(ctx, conv, options)
+		if err != nil {
+			return "", conv, err
+		}
+
+		if len(toolCalls) == 0 {
+			conv = append(conv, common.Conversation{Role: common.ConversationRoleAI, Text: text})
+			return text, conv, nil
+		}
+
+		conv = append(conv, common.Conversation{Role: common.ConversationRoleAI, Text: text, ToolCalls: toCommonToolCalls(toolCalls)})
+		conv = a.executeToolCalls(ctx, conv, toolCalls)
+	}
+
+	return "", conv, fmt.Errorf("agent: reached max iterations (%d) without a final answer", maxIterations)
+}
+
+func (a *Agent) runOnce(ctx context.Context, conv []common.Conversation, options common.ChatOptions) (string, []ai.ToolCall, error) {
+	stream, err := a.Provider.ChatStream(ctx, a.Model, conv, options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var text strings.Builder
+	var toolCalls []ai.ToolCall
+	for {
+		event, err := stream.ReceiveEvent(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return text.String(), toolCalls, nil
+			}
+			return "", nil, err
+		}
+
+		switch event.Type {
+		case ai.StreamEventTypeTextDelta:
+			text.WriteString(event.TextDelta)
+		case ai.StreamEventTypeToolCall:
+			toolCalls = append(toolCalls, event.ToolCall)
+		}
+	}
+}
+
+// toCommonToolCalls copies ai.ToolCall values (the shape ChatStream hands back) into
+// common.ToolCall (the shape a Conversation turn persists), since common can't import ai itself.
+func toCommonToolCalls(toolCalls []ai.ToolCall) []common.ToolCall {
+	converted := make([]common.ToolCall, len(toolCalls))
+	for i, call := range toolCalls {
+		converted[i] = common.ToolCall{ID: call.ID, Name: call.Name, ArgumentsJSON: call.ArgumentsJSON}
+	}
+	return converted
+}
+
+func (a *Agent) executeToolCalls(ctx context.Context, conv []common.Conversation, toolCalls []ai.ToolCall) []common.Conversation {
+	for _, call := range toolCalls {
+		if a.ConfirmToolCall != nil && !a.ConfirmToolCall(call) {
+			conv = append(conv, common.Conversation{Role: common.ConversationRoleTool, ToolCallID: call.ID, Text: "tool call rejected by user"})
+			continue
+		}
+
+		tool, ok := a.Toolbox.find(call.Name)
+		if !ok {
+			conv = append(conv, common.Conversation{Role: common.ConversationRoleTool, ToolCallID: call.ID, Text: fmt.Sprintf("unknown tool %q", call.Name)})
+			continue
+		}
+
+		if a.Toolbox.Executor == nil {
+			conv = append(conv, common.Conversation{Role: common.ConversationRoleTool, ToolCallID: call.ID, Text: "no tool executor configured"})
+			continue
+		}
+
+		result, err := a.Toolbox.Executor.Execute(ctx, tool, call.ArgumentsJSON)
+		if err != nil {
+			result = fmt.Sprintf("tool error: %v", err)
+		}
+		conv = append(conv, common.Conversation{Role: common.ConversationRoleTool, ToolCallID: call.ID, Text: result})
+	}
+	return conv
+}
@@ -0,0 +1,193 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"wox/ai"
+	"wox/common"
+)
+
+// fakeProvider replays one ai.ChatStream per ChatStream call, in order, so a test can script a
+// multi-round tool-call conversation.
+type fakeProvider struct {
+	streams []*fakeStream
+	calls   int
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ai.ChatStream, error) {
+	if f.calls >= len(f.streams) {
+		return nil, fmt.Errorf("fakeProvider: no scripted stream for call %d", f.calls)
+	}
+	stream := f.streams[f.calls]
+	f.calls++
+	return stream, nil
+}
+
+func (f *fakeProvider) Models(ctx context.Context) ([]common.Model, error) { return nil, nil }
+func (f *fakeProvider) Ping(ctx context.Context) error                     { return nil }
+func (f *fakeProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, ai.ErrEmbeddingsNotSupported
+}
+
+type fakeStream struct {
+	events []ai.StreamEvent
+	i      int
+}
+
+func (s *fakeStream) ReceiveEvent(ctx context.Context) (ai.StreamEvent, error) {
+	if s.i >= len(s.events) {
+		return ai.StreamEvent{}, io.EOF
+	}
+	event := s.events[s.i]
+	s.i++
+	return event, nil
+}
+
+func (s *fakeStream) Receive(ctx context.Context) (string, error) {
+	event, err := s.ReceiveEvent(ctx)
+	return event.TextDelta, err
+}
+
+func (s *fakeStream) SetReadDeadline(t time.Time) error { return nil }
+
+type fakeExecutor struct {
+	result string
+	err    error
+}
+
+func (e *fakeExecutor) Execute(ctx context.Context, tool common.MCPTool, argumentsJSON string) (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	return e.result, nil
+}
+
+func textEvent(text string) ai.StreamEvent {
+	return ai.StreamEvent{Type: ai.StreamEventTypeTextDelta, TextDelta: text}
+}
+
+func toolCallEvent(id, name, args string) ai.StreamEvent {
+	return ai.StreamEvent{Type: ai.StreamEventTypeToolCall, ToolCall: ai.ToolCall{ID: id, Name: name, ArgumentsJSON: args}}
+}
+
+func TestAgentRunReturnsPlainTextWithNoToolCalls(t *testing.T) {
+	provider := &fakeProvider{streams: []*fakeStream{{events: []ai.StreamEvent{textEvent("hello")}}}}
+	a := &Agent{Provider: provider, Toolbox: Toolbox{Executor: &fakeExecutor{result: "unused"}}}
+
+	text, conv, err := a.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", text)
+	}
+	if len(conv) != 1 || conv[0].Role != common.ConversationRoleAI || conv[0].Text != "hello" {
+		t.Fatalf("expected a single AI turn, got %+v", conv)
+	}
+}
+
+func TestAgentRunRecordsToolCallsOnTheAssistantTurnEvenWithoutText(t *testing.T) {
+	provider := &fakeProvider{streams: []*fakeStream{
+		{events: []ai.StreamEvent{toolCallEvent("call-1", "search", `{"q":"wox"}`)}},
+		{events: []ai.StreamEvent{textEvent("done")}},
+	}}
+	a := &Agent{
+		Provider: provider,
+		Toolbox: Toolbox{
+			Tools:    []common.MCPTool{{Name: "search"}},
+			Executor: &fakeExecutor{result: "found it"},
+		},
+	}
+
+	_, conv, err := a.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(conv) != 3 {
+		t.Fatalf("expected [assistant tool_calls, tool result, final assistant], got %+v", conv)
+	}
+	if conv[0].Role != common.ConversationRoleAI || len(conv[0].ToolCalls) != 1 || conv[0].ToolCalls[0].ID != "call-1" {
+		t.Fatalf("expected the assistant turn to carry the tool call even with empty text, got %+v", conv[0])
+	}
+	if conv[1].Role != common.ConversationRoleTool || conv[1].ToolCallID != "call-1" || conv[1].Text != "found it" {
+		t.Fatalf("expected a matching tool result turn, got %+v", conv[1])
+	}
+}
+
+func TestAgentRunRejectsToolCallViaConfirmHook(t *testing.T) {
+	provider := &fakeProvider{streams: []*fakeStream{
+		{events: []ai.StreamEvent{toolCallEvent("call-1", "delete_everything", "{}")}},
+		{events: []ai.StreamEvent{textEvent("ok, not deleting")}},
+	}}
+	a := &Agent{
+		Provider:        provider,
+		Toolbox:         Toolbox{Tools: []common.MCPTool{{Name: "delete_everything"}}, Executor: &fakeExecutor{result: "should not run"}},
+		ConfirmToolCall: func(call ai.ToolCall) bool { return false },
+	}
+
+	_, conv, err := a.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conv[1].Text != "tool call rejected by user" {
+		t.Fatalf("expected the rejection to be recorded as the tool result, got %+v", conv[1])
+	}
+}
+
+func TestAgentRunReportsUnknownTool(t *testing.T) {
+	provider := &fakeProvider{streams: []*fakeStream{
+		{events: []ai.StreamEvent{toolCallEvent("call-1", "nonexistent", "{}")}},
+		{events: []ai.StreamEvent{textEvent("done")}},
+	}}
+	a := &Agent{Provider: provider, Toolbox: Toolbox{Executor: &fakeExecutor{}}}
+
+	_, conv, err := a.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(conv[1].Text, "unknown tool") {
+		t.Fatalf("expected the unknown-tool message, got %+v", conv[1])
+	}
+}
+
+func TestAgentRunRecordsNilExecutorAsToolResult(t *testing.T) {
+	provider := &fakeProvider{streams: []*fakeStream{
+		{events: []ai.StreamEvent{toolCallEvent("call-1", "search", "{}")}},
+		{events: []ai.StreamEvent{textEvent("done")}},
+	}}
+	a := &Agent{Provider: provider, Toolbox: Toolbox{Tools: []common.MCPTool{{Name: "search"}}}}
+
+	_, conv, err := a.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conv[1].Text != "no tool executor configured" {
+		t.Fatalf("expected the nil-executor message instead of a panic, got %+v", conv[1])
+	}
+}
+
+func TestAgentRunStopsAtMaxIterations(t *testing.T) {
+	streams := make([]*fakeStream, 3)
+	for i := range streams {
+		streams[i] = &fakeStream{events: []ai.StreamEvent{toolCallEvent(fmt.Sprintf("call-%d", i), "search", "{}")}}
+	}
+	provider := &fakeProvider{streams: streams}
+	a := &Agent{
+		Provider:      provider,
+		Toolbox:       Toolbox{Tools: []common.MCPTool{{Name: "search"}}, Executor: &fakeExecutor{result: "again"}},
+		MaxIterations: 3,
+	}
+
+	_, _, err := a.Run(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "max iterations") {
+		t.Fatalf("expected a max-iterations error, got %v", err)
+	}
+	if provider.calls != 3 {
+		t.Fatalf("expected exactly MaxIterations provider calls, got %d", provider.calls)
+	}
+}
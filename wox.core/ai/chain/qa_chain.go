@@ -0,0 +1,99 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"wox/ai"
+	"wox/common"
+)
+
+const (
+	defaultTopK     = 4
+	defaultTemplate = "Answer using only the following context:\n\n{{docs}}"
+)
+
+// RetrievalQAChain answers a query by retrieving relevant documents and feeding them to a
+// Provider as grounding context.
+type RetrievalQAChain struct {
+	Retriever Retriever
+	Provider  ai.Provider
+	Model     common.Model
+	// Template formats the retrieved documents into the system prompt; it must contain the
+	// placeholder "{{docs}}". Defaults to defaultTemplate.
+	Template string
+	TopK     int
+}
+
+// Run retrieves the TopK documents most relevant to query, formats them into Template, and
+// delegates to Provider.ChatStream. The returned stream emits a StreamEventTypeCitations event
+// listing the retrieved document IDs before the model's own events, so the UI can render
+// footnotes.
+func (c *RetrievalQAChain) Run(ctx context.Context, query string) (ai.ChatStream, error) {
+	topK := c.TopK
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+
+	docs, err := c.Retriever.Retrieve(ctx, query, topK)
+	if err != nil {
+		return nil, err
+	}
+
+	template := c.Template
+	if template == "" {
+		template = defaultTemplate
+	}
+
+	var docsText strings.Builder
+	docIDs := make([]string, len(docs))
+	for i, d := range docs {
+		fmt.Fprintf(&docsText, "[%s] %s\n\n", d.ID, d.Text)
+		docIDs[i] = d.ID
+	}
+	systemPrompt := strings.ReplaceAll(template, "{{docs}}", docsText.String())
+
+	conversation := []common.Conversation{
+		{Role: common.ConversationRoleSystem, Text: systemPrompt},
+		{Role: common.ConversationRoleUser, Text: query},
+	}
+
+	stream, err := c.Provider.ChatStream(ctx, c.Model, conversation, common.ChatOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &citingStream{inner: stream, docIDs: docIDs}, nil
+}
+
+// citingStream prepends a StreamEventTypeCitations event to the wrapped provider stream.
+type citingStream struct {
+	inner   ai.ChatStream
+	docIDs  []string
+	emitted bool
+}
+
+func (s *citingStream) ReceiveEvent(ctx context.Context) (ai.StreamEvent, error) {
+	if !s.emitted {
+		s.emitted = true
+		return ai.StreamEvent{Type: ai.StreamEventTypeCitations, Citations: ai.Citations{DocumentIDs: s.docIDs}}, nil
+	}
+	return s.inner.ReceiveEvent(ctx)
+}
+
+func (s *citingStream) Receive(ctx context.Context) (string, error) {
+	for {
+		event, err := s.ReceiveEvent(ctx)
+		if err != nil {
+			return "", err
+		}
+		if event.Type == ai.StreamEventTypeTextDelta {
+			return event.TextDelta, nil
+		}
+	}
+}
+
+func (s *citingStream) SetReadDeadline(t time.Time) error {
+	return s.inner.SetReadDeadline(t)
+}
@@ -0,0 +1,119 @@
+package chain
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"wox/ai"
+	"wox/common"
+)
+
+type fakeRetriever struct {
+	docs []Document
+}
+
+func (r *fakeRetriever) Retrieve(ctx context.Context, query string, topK int) ([]Document, error) {
+	if topK < len(r.docs) {
+		return r.docs[:topK], nil
+	}
+	return r.docs, nil
+}
+
+type fakeProvider struct {
+	lastConversations []common.Conversation
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ai.ChatStream, error) {
+	f.lastConversations = conversations
+	return &fakeStream{}, nil
+}
+
+func (f *fakeProvider) Models(ctx context.Context) ([]common.Model, error) { return nil, nil }
+func (f *fakeProvider) Ping(ctx context.Context) error                     { return nil }
+func (f *fakeProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, ai.ErrEmbeddingsNotSupported
+}
+
+type fakeStream struct{}
+
+func (s *fakeStream) ReceiveEvent(ctx context.Context) (ai.StreamEvent, error) {
+	return ai.StreamEvent{Type: ai.StreamEventTypeTextDelta, TextDelta: "answer"}, nil
+}
+func (s *fakeStream) Receive(ctx context.Context) (string, error) { return "answer", nil }
+func (s *fakeStream) SetReadDeadline(t time.Time) error           { return nil }
+
+func TestRetrievalQAChainSubstitutesRetrievedDocsIntoTemplate(t *testing.T) {
+	retriever := &fakeRetriever{docs: []Document{{ID: "doc-1", Text: "wox is a launcher"}}}
+	provider := &fakeProvider{}
+	c := &RetrievalQAChain{Retriever: retriever, Provider: provider, Template: "Context:\n{{docs}}\nEnd"}
+
+	if _, err := c.Run(context.Background(), "what is wox?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(provider.lastConversations) != 2 {
+		t.Fatalf("expected a system + user turn, got %+v", provider.lastConversations)
+	}
+	systemText := provider.lastConversations[0].Text
+	if !strings.Contains(systemText, "wox is a launcher") || strings.Contains(systemText, "{{docs}}") {
+		t.Fatalf("expected the template's {{docs}} placeholder to be replaced with retrieved text, got %q", systemText)
+	}
+	if provider.lastConversations[1].Text != "what is wox?" {
+		t.Fatalf("expected the query as the user turn, got %q", provider.lastConversations[1].Text)
+	}
+}
+
+func TestRetrievalQAChainEmitsCitationsBeforeModelEvents(t *testing.T) {
+	retriever := &fakeRetriever{docs: []Document{{ID: "doc-1"}, {ID: "doc-2"}}}
+	c := &RetrievalQAChain{Retriever: retriever, Provider: &fakeProvider{}}
+
+	stream, err := c.Run(context.Background(), "query")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, err := stream.ReceiveEvent(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Type != ai.StreamEventTypeCitations {
+		t.Fatalf("expected the first event to be citations, got %+v", first)
+	}
+	if len(first.Citations.DocumentIDs) != 2 || first.Citations.DocumentIDs[0] != "doc-1" {
+		t.Fatalf("expected both retrieved doc IDs in order, got %+v", first.Citations.DocumentIDs)
+	}
+
+	second, err := stream.ReceiveEvent(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.Type != ai.StreamEventTypeTextDelta || second.TextDelta != "answer" {
+		t.Fatalf("expected the wrapped model event afterwards, got %+v", second)
+	}
+}
+
+func TestRetrievalQAChainDefaultsTopKAndTemplate(t *testing.T) {
+	docs := make([]Document, 10)
+	for i := range docs {
+		docs[i] = Document{ID: strings.Repeat("x", i+1)}
+	}
+	retriever := &fakeRetriever{docs: docs}
+	provider := &fakeProvider{}
+	c := &RetrievalQAChain{Retriever: retriever, Provider: provider}
+
+	stream, err := c.Run(context.Background(), "query")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event, _ := stream.ReceiveEvent(context.Background())
+	if len(event.Citations.DocumentIDs) != defaultTopK {
+		t.Fatalf("expected the default TopK (%d) to cap retrieved docs, got %d", defaultTopK, len(event.Citations.DocumentIDs))
+	}
+
+	systemText := provider.lastConversations[0].Text
+	if !strings.HasPrefix(systemText, "Answer using only the following context:") || strings.Contains(systemText, "{{docs}}") {
+		t.Fatalf("expected the default template with its {{docs}} placeholder filled in, got %q", systemText)
+	}
+}
@@ -0,0 +1,20 @@
+// Package chain composes a Retriever with any ai.Provider to give Wox a "chat with my files"
+// mode, grounding answers in user-supplied documents or notes without separate infrastructure.
+package chain
+
+import "context"
+
+// Document is a single retrievable chunk - a paragraph of a note, a slice of a file - scored
+// against a query by a Retriever.
+type Document struct {
+	ID       string
+	Text     string
+	Metadata map[string]string
+	Score    float32
+}
+
+// Retriever finds the documents most relevant to a query, most relevant first, returning at
+// most topK of them.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string, topK int) ([]Document, error)
+}
@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"context"
+	"math"
+	"sort"
+	"wox/ai"
+)
+
+// VectorStore is an in-process Retriever that embeds documents with a Provider and ranks them
+// by cosine similarity, so retrieval works without deploying a separate vector database.
+type VectorStore struct {
+	provider ai.Provider
+	docs     []storedDoc
+}
+
+type storedDoc struct {
+	ID       string
+	Vector   []float32
+	Metadata map[string]string
+	Text     string
+}
+
+// NewVectorStore returns an empty store that embeds documents and queries with provider.
+func NewVectorStore(provider ai.Provider) *VectorStore {
+	return &VectorStore{provider: provider}
+}
+
+// Add embeds and stores each document's text.
+func (v *VectorStore) Add(ctx context.Context, docs []Document) error {
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Text
+	}
+
+	vectors, err := v.provider.Embed(ctx, texts)
+	if err != nil {
+		return err
+	}
+
+	for i, d := range docs {
+		v.docs = append(v.docs, storedDoc{ID: d.ID, Vector: vectors[i], Metadata: d.Metadata, Text: d.Text})
+	}
+	return nil
+}
+
+// Retrieve embeds query and returns the topK stored documents with the highest cosine
+// similarity to it, most similar first.
+func (v *VectorStore) Retrieve(ctx context.Context, query string, topK int) ([]Document, error) {
+	queryVectors, err := v.provider.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	queryVector := queryVectors[0]
+
+	scored := make([]Document, 0, len(v.docs))
+	for _, d := range v.docs {
+		scored = append(scored, Document{ID: d.ID, Text: d.Text, Metadata: d.Metadata, Score: cosineSimilarity(queryVector, d.Vector)})
+	}
+	sort.Slice(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+
+	if topK > 0 && topK < len(scored) {
+		scored = scored[:topK]
+	}
+	return scored, nil
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
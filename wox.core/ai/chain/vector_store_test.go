@@ -0,0 +1,84 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"wox/ai"
+	"wox/common"
+)
+
+// fakeEmbedder embeds each text by looking it up in a fixed table, so tests control the
+// resulting vectors (and therefore cosine similarity) directly instead of via a real model.
+type fakeEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (f *fakeEmbedder) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ai.ChatStream, error) {
+	return nil, nil
+}
+func (f *fakeEmbedder) Models(ctx context.Context) ([]common.Model, error) { return nil, nil }
+func (f *fakeEmbedder) Ping(ctx context.Context) error                     { return nil }
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = f.vectors[text]
+	}
+	return vectors, nil
+}
+
+func TestVectorStoreRetrieveOrdersByCosineSimilarityDescending(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"exact match": {1, 0},
+		"orthogonal":  {0, 1},
+		"query":       {1, 0},
+	}}
+	store := NewVectorStore(embedder)
+	if err := store.Add(context.Background(), []Document{
+		{ID: "exact", Text: "exact match"},
+		{ID: "unrelated", Text: "orthogonal"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	docs, err := store.Retrieve(context.Background(), "query", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 2 || docs[0].ID != "exact" || docs[1].ID != "unrelated" {
+		t.Fatalf("expected the exact-match document ranked first, got %+v", docs)
+	}
+	if docs[0].Score <= docs[1].Score {
+		t.Fatalf("expected the exact match to score higher than the orthogonal document, got %+v", docs)
+	}
+}
+
+func TestVectorStoreRetrieveCapsAtTopK(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"a": {1, 0}, "b": {1, 0}, "c": {1, 0}, "query": {1, 0},
+	}}
+	store := NewVectorStore(embedder)
+	if err := store.Add(context.Background(), []Document{{ID: "a", Text: "a"}, {ID: "b", Text: "b"}, {ID: "c", Text: "c"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	docs, err := store.Retrieve(context.Background(), "query", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("expected topK to cap results at 2, got %d", len(docs))
+	}
+}
+
+func TestCosineSimilarityZeroForZeroVector(t *testing.T) {
+	if got := cosineSimilarity([]float32{0, 0}, []float32{1, 1}); got != 0 {
+		t.Fatalf("expected cosine similarity against a zero vector to be 0, got %f", got)
+	}
+}
+
+func TestCosineSimilarityOneForIdenticalVectors(t *testing.T) {
+	if got := cosineSimilarity([]float32{3, 4}, []float32{3, 4}); got < 0.999 || got > 1.001 {
+		t.Fatalf("expected cosine similarity of a vector with itself to be ~1, got %f", got)
+	}
+}
@@ -0,0 +1,17 @@
+package ai
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEmbeddingsNotSupported is returned by UnsupportedEmbedder.Embed.
+var ErrEmbeddingsNotSupported = errors.New("ai: provider does not support embeddings")
+
+// UnsupportedEmbedder is embedded by providers that have no embeddings endpoint, so they
+// satisfy Provider's Embed method without each repeating the same stub.
+type UnsupportedEmbedder struct{}
+
+func (UnsupportedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, ErrEmbeddingsNotSupported
+}
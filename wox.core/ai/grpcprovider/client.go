@@ -0,0 +1,239 @@
+package grpcprovider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"wox/ai"
+	"wox/common"
+	"wox/util"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"wox/ai/grpcprovider/pb"
+)
+
+// GRPCProvider satisfies ai.Provider by dialing an out-of-process backend implementing the
+// ai.v1.Provider service, so a provider can ship as a standalone executable instead of being
+// baked into the core binary. It speaks pb's JSON-encoded form of that service, which a Server
+// from this package implements; a plugin built from stubs generated from provider.proto does
+// not - see the package doc.
+type GRPCProvider struct {
+	client pb.ProviderClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCProvider dials addr, a Unix socket ("unix:/run/wox/my-provider.sock") or TCP
+// ("127.0.0.1:4317") endpoint, as configured via setting.AIProvider{Type: "grpc", Address: addr}.
+// It only interoperates with a Server from this same package - see the package doc for why.
+func NewGRPCProvider(ctx context.Context, addr string) (*GRPCProvider, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("grpcprovider: dial %q: %w", addr, err)
+	}
+	return &GRPCProvider{client: pb.NewProviderClient(conn), conn: conn}, nil
+}
+
+// Close tears down the underlying gRPC connection. It does not stop the child process; pair
+// GRPCProvider with a Supervisor for that.
+func (p *GRPCProvider) Close() error {
+	return p.conn.Close()
+}
+
+func (p *GRPCProvider) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ai.ChatStream, error) {
+	requestCtx, cancelRequest := context.WithCancel(ctx)
+
+	stream, err := p.client.ChatStream(requestCtx)
+	if err != nil {
+		cancelRequest()
+		return nil, err
+	}
+
+	if err := stream.Send(toChatRequest(model, conversations, options)); err != nil {
+		cancelRequest()
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		cancelRequest()
+		return nil, err
+	}
+
+	chatStream := &grpcStream{
+		stream:        stream,
+		events:        make(chan grpcRecvResult, 1),
+		cancelRequest: cancelRequest,
+	}
+	util.Go(ctx, "gRPC provider chat stream reader", chatStream.readLoop)
+
+	return chatStream, nil
+}
+
+func (p *GRPCProvider) Models(ctx context.Context) ([]common.Model, error) {
+	resp, err := p.client.Models(ctx, &pb.ModelsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]common.Model, len(resp.ModelNames))
+	for i, name := range resp.ModelNames {
+		models[i] = common.Model{Name: name, Provider: common.ProviderNameGRPC}
+	}
+	return models, nil
+}
+
+func (p *GRPCProvider) Ping(ctx context.Context) error {
+	_, err := p.client.Ping(ctx, &pb.PingRequest{})
+	return err
+}
+
+func (p *GRPCProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := p.client.Embed(ctx, &pb.EmbedRequest{Texts: texts})
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(resp.Vectors))
+	for i, v := range resp.Vectors {
+		vectors[i] = v.Values
+	}
+	return vectors, nil
+}
+
+// grpcStream adapts the bidi pb.Provider_ChatStreamClient to ai.ChatStream. A single readLoop
+// goroutine owns the blocking stream.Recv() call and feeds events over a channel - mirroring
+// GroqProviderStream - rather than spawning a goroutine per ReceiveEvent call, because grpc-go
+// does not allow concurrent Recv() calls on the same stream and a goroutine blocked in Recv()
+// past a deadline/cancellation would otherwise leak.
+type grpcStream struct {
+	stream        pb.Provider_ChatStreamClient
+	events        chan grpcRecvResult
+	cancelRequest context.CancelFunc
+
+	deadlineMu sync.Mutex
+	deadline   time.Time
+}
+
+type grpcRecvResult struct {
+	event *pb.Event
+	err   error
+}
+
+// readLoop repeatedly calls stream.Recv(), which is only safe to call from one goroutine at a
+// time, and forwards every result to events until Recv() returns an error (including io.EOF).
+func (s *grpcStream) readLoop() {
+	defer close(s.events)
+	for {
+		event, err := s.stream.Recv()
+		s.events <- grpcRecvResult{event: event, err: err}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *grpcStream) SetReadDeadline(t time.Time) error {
+	s.deadlineMu.Lock()
+	defer s.deadlineMu.Unlock()
+	s.deadline = t
+	return nil
+}
+
+func (s *grpcStream) ReceiveEvent(ctx context.Context) (ai.StreamEvent, error) {
+	s.deadlineMu.Lock()
+	deadline := s.deadline
+	s.deadlineMu.Unlock()
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return ai.StreamEvent{}, &ai.DeadlineError{}
+	}
+
+	var deadlineC <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		deadlineC = timer.C
+	}
+
+	select {
+	case r, ok := <-s.events:
+		if !ok {
+			return ai.StreamEvent{}, io.EOF
+		}
+		if r.err != nil {
+			if r.err == io.EOF {
+				return ai.StreamEvent{}, io.EOF
+			}
+			return ai.StreamEvent{}, r.err
+		}
+		return fromEvent(r.event), nil
+	case <-deadlineC:
+		return ai.StreamEvent{}, &ai.DeadlineError{}
+	case <-ctx.Done():
+		s.cancelRequest()
+		return ai.StreamEvent{}, ctx.Err()
+	}
+}
+
+func (s *grpcStream) Receive(ctx context.Context) (string, error) {
+	for {
+		event, err := s.ReceiveEvent(ctx)
+		if err != nil {
+			return "", err
+		}
+		if event.Type == ai.StreamEventTypeTextDelta {
+			return event.TextDelta, nil
+		}
+	}
+}
+
+func toChatRequest(model common.Model, conversations []common.Conversation, options common.ChatOptions) *pb.ChatRequest {
+	messages := make([]*pb.ConversationMessage, len(conversations))
+	for i, c := range conversations {
+		var toolCalls []*pb.ToolCall
+		for _, call := range c.ToolCalls {
+			toolCalls = append(toolCalls, &pb.ToolCall{Id: call.ID, Name: call.Name, ArgumentsJson: call.ArgumentsJSON})
+		}
+		messages[i] = &pb.ConversationMessage{Role: string(c.Role), Text: c.Text, ToolCallId: c.ToolCallID, ToolCalls: toolCalls}
+	}
+
+	tools := make([]*pb.Tool, len(options.Tools))
+	for i, t := range options.Tools {
+		tools[i] = &pb.Tool{Name: t.Name, Description: t.Description}
+	}
+
+	return &pb.ChatRequest{Model: model.Name, Conversation: messages, Tools: tools}
+}
+
+func fromEvent(event *pb.Event) ai.StreamEvent {
+	switch ai.StreamEventType(event.Type) {
+	case ai.StreamEventTypeTextDelta:
+		return ai.StreamEvent{Type: ai.StreamEventTypeTextDelta, TextDelta: event.TextDelta}
+	case ai.StreamEventTypeToolCall:
+		return ai.StreamEvent{Type: ai.StreamEventTypeToolCall, ToolCall: ai.ToolCall{
+			ID:            event.ToolCall.Id,
+			Name:          event.ToolCall.Name,
+			ArgumentsJSON: event.ToolCall.ArgumentsJson,
+		}}
+	case ai.StreamEventTypeToolCallDelta:
+		return ai.StreamEvent{Type: ai.StreamEventTypeToolCallDelta, ToolCallDelta: ai.ToolCallDelta{
+			Index:        int(event.ToolCallDelta.Index),
+			ID:           event.ToolCallDelta.Id,
+			Name:         event.ToolCallDelta.Name,
+			ArgsFragment: event.ToolCallDelta.ArgsFragment,
+		}}
+	case ai.StreamEventTypeUsage:
+		return ai.StreamEvent{Type: ai.StreamEventTypeUsage, Usage: ai.Usage{
+			PromptTokens:     int(event.Usage.PromptTokens),
+			CompletionTokens: int(event.Usage.CompletionTokens),
+			TotalTokens:      int(event.Usage.TotalTokens),
+		}}
+	case ai.StreamEventTypeFinish:
+		return ai.StreamEvent{Type: ai.StreamEventTypeFinish, Finish: ai.Finish{Reason: event.Finish.Reason}}
+	default:
+		util.GetLogger().Warn(util.NewTraceContext(), fmt.Sprintf("grpcprovider: unknown event type %q", event.Type))
+		return ai.StreamEvent{}
+	}
+}
@@ -0,0 +1,13 @@
+// Package grpcprovider implements the ai.v1.Provider gRPC plugin protocol: a GRPCProvider that
+// dials an out-of-process backend and satisfies ai.Provider, a Server helper for implementing
+// one in Go, and a Supervisor that spawns and restarts the child process.
+//
+// # Wire format: Go plugins only
+//
+// provider.proto declares the service and its messages. The pb package is not generated from
+// it: pb is hand-written Go structs carried over a custom JSON grpc codec (pb/codec.go) instead
+// of the protobuf binary wire format. A Go plugin built against pb (client.go talking to a
+// Server from this same package) works. A plugin generated from provider.proto with
+// protoc/tonic/grpc-python negotiates the standard protobuf-binary subtype and cannot talk to
+// either side of pb, so plugins written in other languages are not supported.
+package grpcprovider
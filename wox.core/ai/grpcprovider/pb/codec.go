@@ -0,0 +1,31 @@
+package pb
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is registered with grpc-go via encoding.RegisterCodec and selected per-call with
+// grpc.CallContentSubtype(codecName) (see callOption in service.go), so every ai.v1.Provider RPC in this
+// package is carried as JSON instead of the protobuf binary wire format - see the package doc
+// comment in types.go for why.
+const codecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
@@ -0,0 +1,224 @@
+package pb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// serviceName matches the "ai.v1.Provider" service name declared in provider.proto. Messages
+// on that service are JSON-encoded (see callOption below), so stubs generated from
+// provider.proto, which use the protobuf binary encoding, do not interoperate with this package.
+const serviceName = "ai.v1.Provider"
+
+// callOption is applied to every unary and streaming call so it's carried over jsonCodec
+// regardless of what codec the grpc.ClientConn/grpc.Server defaults to.
+var callOption = grpc.CallContentSubtype(codecName)
+
+// ProviderClient is the client API for the ai.v1.Provider service.
+type ProviderClient interface {
+	ChatStream(ctx context.Context, opts ...grpc.CallOption) (Provider_ChatStreamClient, error)
+	Models(ctx context.Context, in *ModelsRequest, opts ...grpc.CallOption) (*ModelsResponse, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error)
+}
+
+type providerClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewProviderClient returns a ProviderClient that dials the ai.v1.Provider service over cc.
+func NewProviderClient(cc *grpc.ClientConn) ProviderClient {
+	return &providerClient{cc: cc}
+}
+
+func (c *providerClient) ChatStream(ctx context.Context, opts ...grpc.CallOption) (Provider_ChatStreamClient, error) {
+	opts = append(opts, callOption)
+	stream, err := c.cc.NewStream(ctx, &grpc.StreamDesc{StreamName: "ChatStream", ClientStreams: true, ServerStreams: true}, "/"+serviceName+"/ChatStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &providerChatStreamClient{ClientStream: stream}, nil
+}
+
+func (c *providerClient) Models(ctx context.Context, in *ModelsRequest, opts ...grpc.CallOption) (*ModelsResponse, error) {
+	opts = append(opts, callOption)
+	out := new(ModelsResponse)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Models", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *providerClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	opts = append(opts, callOption)
+	out := new(PingResponse)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Ping", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *providerClient) Embed(ctx context.Context, in *EmbedRequest, opts ...grpc.CallOption) (*EmbedResponse, error) {
+	opts = append(opts, callOption)
+	out := new(EmbedResponse)
+	if err := c.cc.Invoke(ctx, "/"+serviceName+"/Embed", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Provider_ChatStreamClient is the bidi stream handle returned by ProviderClient.ChatStream.
+type Provider_ChatStreamClient interface {
+	Send(*ChatRequest) error
+	Recv() (*Event, error)
+	CloseSend() error
+	grpc.ClientStream
+}
+
+type providerChatStreamClient struct {
+	grpc.ClientStream
+}
+
+func (s *providerChatStreamClient) Send(req *ChatRequest) error {
+	return s.ClientStream.SendMsg(req)
+}
+
+func (s *providerChatStreamClient) Recv() (*Event, error) {
+	event := new(Event)
+	if err := s.ClientStream.RecvMsg(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// ProviderServer is the server API for the ai.v1.Provider service.
+type ProviderServer interface {
+	ChatStream(Provider_ChatStreamServer) error
+	Models(context.Context, *ModelsRequest) (*ModelsResponse, error)
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	Embed(context.Context, *EmbedRequest) (*EmbedResponse, error)
+}
+
+// UnimplementedProviderServer should be embedded in a ProviderServer implementation to satisfy
+// forward compatibility: a new RPC added to this package won't break existing implementers
+// until they choose to implement it.
+type UnimplementedProviderServer struct{}
+
+func (UnimplementedProviderServer) ChatStream(Provider_ChatStreamServer) error {
+	return errUnimplemented("ChatStream")
+}
+
+func (UnimplementedProviderServer) Models(context.Context, *ModelsRequest) (*ModelsResponse, error) {
+	return nil, errUnimplemented("Models")
+}
+
+func (UnimplementedProviderServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, errUnimplemented("Ping")
+}
+
+func (UnimplementedProviderServer) Embed(context.Context, *EmbedRequest) (*EmbedResponse, error) {
+	return nil, errUnimplemented("Embed")
+}
+
+// Provider_ChatStreamServer is the bidi stream handle passed to ProviderServer.ChatStream.
+type Provider_ChatStreamServer interface {
+	Send(*Event) error
+	Recv() (*ChatRequest, error)
+	grpc.ServerStream
+}
+
+type providerChatStreamServer struct {
+	grpc.ServerStream
+}
+
+func (s *providerChatStreamServer) Send(event *Event) error {
+	return s.ServerStream.SendMsg(event)
+}
+
+func (s *providerChatStreamServer) Recv() (*ChatRequest, error) {
+	req := new(ChatRequest)
+	if err := s.ServerStream.RecvMsg(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// RegisterProviderServer registers srv's implementation of the ai.v1.Provider service against
+// grpcServer.
+func RegisterProviderServer(grpcServer *grpc.Server, srv ProviderServer) {
+	grpcServer.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*ProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Models",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(ModelsRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(ProviderServer).Models(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Models"}, func(ctx context.Context, req any) (any, error) {
+					return srv.(ProviderServer).Models(ctx, req.(*ModelsRequest))
+				})
+			},
+		},
+		{
+			MethodName: "Ping",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(PingRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(ProviderServer).Ping(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Ping"}, func(ctx context.Context, req any) (any, error) {
+					return srv.(ProviderServer).Ping(ctx, req.(*PingRequest))
+				})
+			},
+		},
+		{
+			MethodName: "Embed",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(EmbedRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(ProviderServer).Embed(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Embed"}, func(ctx context.Context, req any) (any, error) {
+					return srv.(ProviderServer).Embed(ctx, req.(*EmbedRequest))
+				})
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ChatStream",
+			ClientStreams: true,
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				return srv.(ProviderServer).ChatStream(&providerChatStreamServer{ServerStream: stream})
+			},
+		},
+	},
+	Metadata: "provider.proto",
+}
+
+type unimplementedError string
+
+func (e unimplementedError) Error() string {
+	return "grpcprovider: " + string(e) + " not implemented"
+}
+
+func errUnimplemented(method string) error {
+	return unimplementedError(method)
+}
@@ -0,0 +1,83 @@
+// Package pb implements the wire types and gRPC service descriptor for ai.v1.Provider, described
+// in ../provider.proto. It is hand-maintained rather than protoc-generated: this build
+// environment has no protoc/protoc-gen-go-grpc available, so the types here are plain Go
+// structs carried over the jsonCodec (see codec.go) instead of the protobuf binary wire format.
+// A Go plugin built against this package interoperates with GRPCProvider/Server; a plugin
+// built from protobuf stubs generated from provider.proto (Python, Rust, ...) uses the protobuf
+// binary encoding and does not.
+package pb
+
+// ChatRequest mirrors the ChatRequest message in provider.proto.
+type ChatRequest struct {
+	Model        string                 `json:"model"`
+	Conversation []*ConversationMessage `json:"conversation"`
+	Tools        []*Tool                `json:"tools"`
+}
+
+type ConversationMessage struct {
+	Role       string      `json:"role"`
+	Text       string      `json:"text"`
+	ToolCallId string      `json:"tool_call_id"`
+	ToolCalls  []*ToolCall `json:"tool_calls,omitempty"`
+}
+
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Event mirrors ai.StreamEvent: exactly one field below is set, chosen by Type.
+type Event struct {
+	Type string `json:"type"`
+
+	TextDelta     string         `json:"text_delta,omitempty"`
+	ToolCall      *ToolCall      `json:"tool_call,omitempty"`
+	ToolCallDelta *ToolCallDelta `json:"tool_call_delta,omitempty"`
+	Usage         *Usage         `json:"usage,omitempty"`
+	Finish        *Finish        `json:"finish,omitempty"`
+}
+
+type ToolCall struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	ArgumentsJson string `json:"arguments_json"`
+}
+
+type ToolCallDelta struct {
+	Index        int32  `json:"index"`
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	ArgsFragment string `json:"args_fragment"`
+}
+
+type Usage struct {
+	PromptTokens     int32 `json:"prompt_tokens"`
+	CompletionTokens int32 `json:"completion_tokens"`
+	TotalTokens      int32 `json:"total_tokens"`
+}
+
+type Finish struct {
+	Reason string `json:"reason"`
+}
+
+type ModelsRequest struct{}
+
+type ModelsResponse struct {
+	ModelNames []string `json:"model_names"`
+}
+
+type PingRequest struct{}
+
+type PingResponse struct{}
+
+type EmbedRequest struct {
+	Texts []string `json:"texts"`
+}
+
+type EmbedResponse struct {
+	Vectors []*Vector `json:"vectors"`
+}
+
+type Vector struct {
+	Values []float32 `json:"values"`
+}
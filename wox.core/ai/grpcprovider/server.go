@@ -0,0 +1,137 @@
+package grpcprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"wox/ai"
+	"wox/common"
+
+	"google.golang.org/grpc"
+
+	"wox/ai/grpcprovider/pb"
+)
+
+// Server exposes a Go ai.Provider implementation as an ai.v1.Provider gRPC service, so writing a
+// Wox provider plugin in Go needs no protobuf boilerplate from the implementer. Only a
+// GRPCProvider from this same package can talk to it - see the package doc for why.
+type Server struct {
+	pb.UnimplementedProviderServer
+	provider ai.Provider
+}
+
+// NewServer wraps provider so it can be served with Serve.
+func NewServer(provider ai.Provider) *Server {
+	return &Server{provider: provider}
+}
+
+// Serve registers the server against a fresh grpc.Server and blocks accepting connections on
+// listener until the server is stopped or the listener errors.
+func (s *Server) Serve(listener net.Listener) error {
+	grpcServer := grpc.NewServer()
+	pb.RegisterProviderServer(grpcServer, s)
+	return grpcServer.Serve(listener)
+}
+
+func (s *Server) ChatStream(stream pb.Provider_ChatStreamServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	chatStream, err := s.provider.ChatStream(stream.Context(), common.Model{Name: req.Model}, fromChatRequest(req), toChatOptions(req))
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := chatStream.ReceiveEvent(stream.Context())
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := stream.Send(toEvent(event)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) Models(ctx context.Context, _ *pb.ModelsRequest) (*pb.ModelsResponse, error) {
+	models, err := s.provider.Models(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(models))
+	for i, m := range models {
+		names[i] = m.Name
+	}
+	return &pb.ModelsResponse{ModelNames: names}, nil
+}
+
+func (s *Server) Ping(ctx context.Context, _ *pb.PingRequest) (*pb.PingResponse, error) {
+	if err := s.provider.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &pb.PingResponse{}, nil
+}
+
+func (s *Server) Embed(ctx context.Context, req *pb.EmbedRequest) (*pb.EmbedResponse, error) {
+	vectors, err := s.provider.Embed(ctx, req.Texts)
+	if err != nil {
+		return nil, err
+	}
+	pbVectors := make([]*pb.Vector, len(vectors))
+	for i, v := range vectors {
+		pbVectors[i] = &pb.Vector{Values: v}
+	}
+	return &pb.EmbedResponse{Vectors: pbVectors}, nil
+}
+
+func fromChatRequest(req *pb.ChatRequest) []common.Conversation {
+	conversations := make([]common.Conversation, len(req.Conversation))
+	for i, m := range req.Conversation {
+		var toolCalls []common.ToolCall
+		for _, call := range m.ToolCalls {
+			toolCalls = append(toolCalls, common.ToolCall{ID: call.Id, Name: call.Name, ArgumentsJSON: call.ArgumentsJson})
+		}
+		conversations[i] = common.Conversation{Role: common.ConversationRole(m.Role), Text: m.Text, ToolCallID: m.ToolCallId, ToolCalls: toolCalls}
+	}
+	return conversations
+}
+
+func toChatOptions(req *pb.ChatRequest) common.ChatOptions {
+	tools := make([]common.MCPTool, len(req.Tools))
+	for i, t := range req.Tools {
+		tools[i] = common.MCPTool{Name: t.Name, Description: t.Description}
+	}
+	return common.ChatOptions{Tools: tools}
+}
+
+func toEvent(event ai.StreamEvent) *pb.Event {
+	pbEvent := &pb.Event{Type: string(event.Type)}
+	switch event.Type {
+	case ai.StreamEventTypeTextDelta:
+		pbEvent.TextDelta = event.TextDelta
+	case ai.StreamEventTypeToolCall:
+		pbEvent.ToolCall = &pb.ToolCall{Id: event.ToolCall.ID, Name: event.ToolCall.Name, ArgumentsJson: event.ToolCall.ArgumentsJSON}
+	case ai.StreamEventTypeToolCallDelta:
+		pbEvent.ToolCallDelta = &pb.ToolCallDelta{
+			Index:        int32(event.ToolCallDelta.Index),
+			Id:           event.ToolCallDelta.ID,
+			Name:         event.ToolCallDelta.Name,
+			ArgsFragment: event.ToolCallDelta.ArgsFragment,
+		}
+	case ai.StreamEventTypeUsage:
+		pbEvent.Usage = &pb.Usage{
+			PromptTokens:     int32(event.Usage.PromptTokens),
+			CompletionTokens: int32(event.Usage.CompletionTokens),
+			TotalTokens:      int32(event.Usage.TotalTokens),
+		}
+	case ai.StreamEventTypeFinish:
+		pbEvent.Finish = &pb.Finish{Reason: event.Finish.Reason}
+	}
+	return pbEvent
+}
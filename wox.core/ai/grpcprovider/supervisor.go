@@ -0,0 +1,85 @@
+package grpcprovider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+	"wox/util"
+)
+
+const (
+	supervisorBaseBackoff = time.Second
+	supervisorMaxBackoff  = 30 * time.Second
+)
+
+// Supervisor spawns a provider plugin child process and keeps it running, modeled after
+// HashiCorp go-plugin's lifecycle: restart on crash with exponential backoff, and forward the
+// child's stderr to Wox's own logs so a misbehaving plugin is debuggable without a separate
+// terminal.
+type Supervisor struct {
+	// Command and Args launch the plugin, e.g. a Python interpreter running a provider script.
+	Command string
+	Args    []string
+}
+
+// Run launches the child process and restarts it whenever it exits, until ctx is cancelled.
+// It never returns successfully; it returns nil only when ctx is done.
+func (s *Supervisor) Run(ctx context.Context) error {
+	restarts := 0
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		started := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// A process that stayed up for a while crashing doesn't indicate a crash loop; reset
+		// the backoff so a one-off crash doesn't leave the plugin waiting half a minute to come
+		// back after it's actually fine again.
+		if time.Since(started) > supervisorMaxBackoff {
+			restarts = 0
+		}
+
+		backoff := supervisorBaseBackoff << uint(restarts)
+		if backoff <= 0 || backoff > supervisorMaxBackoff {
+			backoff = supervisorMaxBackoff
+		}
+		restarts++
+
+		util.GetLogger().Warn(util.NewTraceContext(), fmt.Sprintf("grpcprovider: %s exited (%v), restarting in %s", s.Command, err, backoff))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (s *Supervisor) runOnce(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	util.Go(ctx, "grpcprovider stderr", func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			util.GetLogger().Info(util.NewTraceContext(), fmt.Sprintf("grpcprovider[%s]: %s", s.Command, scanner.Text()))
+		}
+	})
+
+	return cmd.Wait()
+}
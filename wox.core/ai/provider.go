@@ -0,0 +1,44 @@
+package ai
+
+import (
+	"context"
+	"time"
+	"wox/common"
+)
+
+// Provider is implemented by every AI backend Wox can talk to (Groq, OpenAI, a local Ollama, ...).
+type Provider interface {
+	ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ChatStream, error)
+	Models(ctx context.Context) ([]common.Model, error)
+	Ping(ctx context.Context) error
+
+	// Embed turns texts into vectors for retrieval/similarity use cases (see ai/chain).
+	// Providers with no embeddings endpoint embed UnsupportedEmbedder to get a default
+	// implementation that returns ErrEmbeddingsNotSupported.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// ChatStream is returned by Provider.ChatStream and yields model output incrementally.
+type ChatStream interface {
+	// ReceiveEvent returns the next structured event from the model (text delta, tool call,
+	// usage or finish). It returns io.EOF once the stream is exhausted.
+	ReceiveEvent(ctx context.Context) (StreamEvent, error)
+
+	// Receive is a backwards-compatible view over ReceiveEvent that flattens every event down
+	// to its text representation. Tool calls, usage and finish events are silently skipped, so
+	// callers that only care about the displayed text don't need to change.
+	Receive(ctx context.Context) (string, error)
+
+	// SetReadDeadline bounds how long ReceiveEvent/Receive will wait for the next event,
+	// matching net.Conn semantics: a zero Time clears any deadline. Once it elapses, the next
+	// call returns a *DeadlineError.
+	SetReadDeadline(t time.Time) error
+}
+
+// DeadlineError is returned by ReceiveEvent/Receive once a deadline set with SetReadDeadline
+// has elapsed. It satisfies net.Error so callers that already special-case timeouts keep working.
+type DeadlineError struct{}
+
+func (*DeadlineError) Error() string   { return "ai: read deadline exceeded" }
+func (*DeadlineError) Timeout() bool   { return true }
+func (*DeadlineError) Temporary() bool { return true }
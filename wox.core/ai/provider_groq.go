@@ -5,12 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 	"wox/common"
 	"wox/setting"
 	"wox/util"
 
-	"github.com/djherbis/buffer"
-	"github.com/djherbis/nio/v3"
 	"github.com/tidwall/gjson"
 	"github.com/tmc/langchaingo/jsonschema"
 	"github.com/tmc/langchaingo/llms"
@@ -20,12 +20,19 @@ import (
 const groqBaseUrl = "https://api.groq.com/openai/v1"
 
 type GroqProvider struct {
+	UnsupportedEmbedder // Groq has no embeddings endpoint
+
 	connectContext setting.AIProvider
 }
 
 type GroqProviderStream struct {
 	conversations []common.Conversation
-	reader        io.Reader
+	events        chan StreamEvent
+	err           error
+	cancelRequest context.CancelFunc
+
+	deadlineMu sync.Mutex
+	deadline   time.Time
 }
 
 func NewGroqProvider(ctx context.Context, connectContext setting.AIProvider) Provider {
@@ -43,21 +50,79 @@ func (g *GroqProvider) ChatStream(ctx context.Context, model common.Model, conve
 		return nil, clientErr
 	}
 
-	buf := buffer.New(4 * 1024) // 4KB In memory Buffer
-	r, w := nio.Pipe(buf)
+	requestCtx, cancelRequest := context.WithCancel(ctx)
+	stream := &GroqProviderStream{
+		conversations: conversations,
+		events:        make(chan StreamEvent, 16),
+		cancelRequest: cancelRequest,
+	}
 	util.Go(ctx, "Groq chat stream", func() {
-		_, err := client.GenerateContent(ctx, g.convertConversations(conversations), llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
-			w.Write(chunk)
+		defer close(stream.events)
+		defer cancelRequest()
+
+		resp, err := client.GenerateContent(requestCtx, g.convertConversations(conversations), llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			if len(chunk) > 0 {
+				stream.events <- StreamEvent{Type: StreamEventTypeTextDelta, TextDelta: string(chunk)}
+			}
 			return nil
 		}), llms.WithTools(g.convertTools(options.Tools)))
 		if err != nil {
-			w.CloseWithError(err)
-		} else {
-			w.Close()
+			stream.err = err
+			return
 		}
+
+		g.emitToolCallsAndUsage(stream, resp)
 	})
 
-	return &GroqProviderStream{conversations: conversations, reader: r}, nil
+	return stream, nil
+}
+
+// emitToolCallsAndUsage turns the aggregated response langchaingo hands back once the stream
+// finishes into the richer events Receive callers can't get mid-flight: tool calls the model
+// wants to invoke, the finish reason, and token usage for billing/limit UI.
+//
+// langchaingo's WithStreamingFunc callback only ever surfaces plain text chunks for this
+// backend, not per-token tool-call argument fragments, so there's no ToolCallDelta to emit here
+// - tool calls only become visible once GenerateContent returns them fully assembled. A backend
+// whose client exposes real incremental tool-call deltas should emit ToolCallDelta events from
+// its streaming callback instead of waiting for the response like this.
+func (g *GroqProvider) emitToolCallsAndUsage(stream *GroqProviderStream, resp *llms.ContentResponse) {
+	for _, choice := range resp.Choices {
+		for _, tc := range choice.ToolCalls {
+			stream.events <- StreamEvent{
+				Type: StreamEventTypeToolCall,
+				ToolCall: ToolCall{
+					ID:            tc.ID,
+					Name:          tc.FunctionCall.Name,
+					ArgumentsJSON: tc.FunctionCall.Arguments,
+				},
+			}
+		}
+
+		if promptTokens, completionTokens, ok := extractUsage(choice.GenerationInfo); ok {
+			stream.events <- StreamEvent{
+				Type: StreamEventTypeUsage,
+				Usage: Usage{
+					PromptTokens:     promptTokens,
+					CompletionTokens: completionTokens,
+					TotalTokens:      promptTokens + completionTokens,
+				},
+			}
+		}
+
+		if choice.StopReason != "" {
+			stream.events <- StreamEvent{Type: StreamEventTypeFinish, Finish: Finish{Reason: choice.StopReason}}
+		}
+	}
+}
+
+func extractUsage(info map[string]any) (promptTokens int, completionTokens int, ok bool) {
+	if info == nil {
+		return 0, 0, false
+	}
+	p, pOk := info["PromptTokens"].(int)
+	c, cOk := info["CompletionTokens"].(int)
+	return p, c, pOk || cOk
 }
 
 func (g *GroqProvider) convertTools(tools []common.MCPTool) []llms.Tool {
@@ -151,24 +216,95 @@ func (g *GroqProvider) convertConversations(conversations []common.Conversation)
 			chatMessages = append(chatMessages, llms.TextParts(llms.ChatMessageTypeHuman, conversation.Text))
 		}
 		if conversation.Role == common.ConversationRoleAI {
-			chatMessages = append(chatMessages, llms.TextParts(llms.ChatMessageTypeAI, conversation.Text))
+			parts := []llms.ContentPart{llms.TextContent{Text: conversation.Text}}
+			for _, call := range conversation.ToolCalls {
+				parts = append(parts, llms.ToolCall{
+					ID:           call.ID,
+					Type:         "function",
+					FunctionCall: &llms.FunctionCall{Name: call.Name, Arguments: call.ArgumentsJSON},
+				})
+			}
+			chatMessages = append(chatMessages, llms.MessageContent{Role: llms.ChatMessageTypeAI, Parts: parts})
+		}
+		if conversation.Role == common.ConversationRoleSystem {
+			chatMessages = append(chatMessages, llms.TextParts(llms.ChatMessageTypeSystem, conversation.Text))
+		}
+		if conversation.Role == common.ConversationRoleTool {
+			chatMessages = append(chatMessages, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{
+					llms.ToolCallResponse{
+						ToolCallID: conversation.ToolCallID,
+						Content:    conversation.Text,
+					},
+				},
+			})
 		}
 	}
 
 	return chatMessages
 }
 
-func (s *GroqProviderStream) Receive(ctx context.Context) (string, error) {
-	buf := make([]byte, 2048)
-	n, err := s.reader.Read(buf)
-	if err != nil {
-		if errors.Is(err, io.EOF) {
-			return "", io.EOF
+// SetReadDeadline bounds how long ReceiveEvent/Receive will wait for the next event. A zero
+// Time, the default, waits forever; calling it again with a zero Time clears a previously set
+// deadline so the same stream can continue.
+func (s *GroqProviderStream) SetReadDeadline(t time.Time) error {
+	s.deadlineMu.Lock()
+	defer s.deadlineMu.Unlock()
+	s.deadline = t
+	return nil
+}
+
+func (s *GroqProviderStream) ReceiveEvent(ctx context.Context) (StreamEvent, error) {
+	s.deadlineMu.Lock()
+	deadline := s.deadline
+	s.deadlineMu.Unlock()
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return StreamEvent{}, &DeadlineError{}
+	}
+
+	var deadlineC <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		deadlineC = timer.C
+	}
+
+	select {
+	case event, ok := <-s.events:
+		if !ok {
+			if s.err != nil {
+				return StreamEvent{}, s.err
+			}
+			return StreamEvent{}, io.EOF
 		}
-		return "", err
+		util.GetLogger().Debug(util.NewTraceContext(), fmt.Sprintf("Groq: Send event: %+v", event))
+		return event, nil
+	case <-deadlineC:
+		return StreamEvent{}, &DeadlineError{}
+	case <-ctx.Done():
+		// Cancelling the caller's context (e.g. the TUI on Esc) kills the in-flight Groq
+		// request too, instead of letting it drain into a buffer nobody reads anymore.
+		s.cancelRequest()
+		return StreamEvent{}, ctx.Err()
 	}
+}
 
-	resp := string(buf[:n])
-	util.GetLogger().Debug(util.NewTraceContext(), fmt.Sprintf("Groq: Send response: %s", resp))
-	return resp, nil
+// Receive flattens ReceiveEvent down to plain text for callers that only display the model's
+// reply and don't need tool calls, usage or finish events.
+func (s *GroqProviderStream) Receive(ctx context.Context) (string, error) {
+	for {
+		event, err := s.ReceiveEvent(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", io.EOF
+			}
+			return "", err
+		}
+
+		if event.Type == StreamEventTypeTextDelta {
+			return event.TextDelta, nil
+		}
+	}
 }
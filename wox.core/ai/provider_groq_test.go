@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestGroqStream() (*GroqProviderStream, *bool) {
+	canceled := false
+	return &GroqProviderStream{
+		events:        make(chan StreamEvent, 2),
+		cancelRequest: func() { canceled = true },
+	}, &canceled
+}
+
+func TestGroqProviderStreamReceiveEventDeliversQueuedEvent(t *testing.T) {
+	stream, _ := newTestGroqStream()
+	stream.events <- StreamEvent{Type: StreamEventTypeTextDelta, TextDelta: "hi"}
+
+	event, err := stream.ReceiveEvent(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.TextDelta != "hi" {
+		t.Fatalf("expected the queued event, got %+v", event)
+	}
+}
+
+func TestGroqProviderStreamReceiveEventTimesOutOnExpiredDeadline(t *testing.T) {
+	stream, _ := newTestGroqStream()
+	if err := stream.SetReadDeadline(time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := stream.ReceiveEvent(context.Background())
+	var deadlineErr *DeadlineError
+	if !errors.As(err, &deadlineErr) {
+		t.Fatalf("expected a *DeadlineError, got %v", err)
+	}
+}
+
+func TestGroqProviderStreamReceiveEventCancelsRequestOnCallerCancellation(t *testing.T) {
+	stream, canceled := newTestGroqStream()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := stream.ReceiveEvent(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !*canceled {
+		t.Fatalf("expected the caller cancelling ctx to cancel the in-flight Groq request")
+	}
+}
+
+func TestGroqProviderStreamReceiveFlattensToTextDelta(t *testing.T) {
+	stream, _ := newTestGroqStream()
+	stream.events <- StreamEvent{Type: StreamEventTypeFinish, Finish: Finish{Reason: "stop"}}
+	stream.events <- StreamEvent{Type: StreamEventTypeTextDelta, TextDelta: "hello"}
+
+	text, err := stream.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "hello" {
+		t.Fatalf("expected Receive to skip non-text events and return %q, got %q", "hello", text)
+	}
+}
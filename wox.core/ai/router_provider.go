@@ -0,0 +1,358 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"wox/common"
+	"wox/util"
+)
+
+// RoutingPolicy picks which healthy backend a RouterProvider hands a request to.
+type RoutingPolicy string
+
+const (
+	// RoutingPolicyPriority always tries backends in the order they were configured, falling
+	// through to the next one only when the preferred backend is unhealthy.
+	RoutingPolicyPriority   RoutingPolicy = "priority"
+	RoutingPolicyRoundRobin RoutingPolicy = "round_robin"
+	RoutingPolicyWeighted   RoutingPolicy = "weighted"
+	// RoutingPolicyLeastLatency prefers the backend with the lowest latency recorded by its most
+	// recent success: time to first event for ChatStream, 0 for a successful Ping.
+	RoutingPolicyLeastLatency RoutingPolicy = "least_latency"
+)
+
+// RouterBackend is one provider a RouterProvider can route requests to.
+type RouterBackend struct {
+	Name     string
+	Provider Provider
+	// Weight is only consulted under RoutingPolicyWeighted; backends default to weight 1.
+	Weight int
+}
+
+const (
+	baseBackoff = 2 * time.Second
+	maxBackoff  = 2 * time.Minute
+)
+
+// health tracks one backend's recent reliability so RouterProvider can skip it while it's
+// misbehaving and bring it back automatically once it recovers.
+type health struct {
+	mu               sync.Mutex
+	consecutiveFails int
+	permanentlyDown  bool // set on 401/403 - the key is wrong, retrying won't help
+	backoffUntil     time.Time
+	latency          time.Duration
+}
+
+func (h *health) isHealthy() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.permanentlyDown && time.Now().After(h.backoffUntil)
+}
+
+func (h *health) recordSuccess(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFails = 0
+	h.permanentlyDown = false
+	h.backoffUntil = time.Time{}
+	h.latency = latency
+}
+
+func (h *health) recordFailure(err error) {
+	if isCallerCancellation(err) {
+		// The caller gave up (e.g. ctx.Done() from an Esc-cancellation), not the backend - don't
+		// let that count against a perfectly healthy backend's error budget.
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if isAuthError(err) {
+		h.permanentlyDown = true
+		return
+	}
+
+	h.consecutiveFails++
+	backoff := baseBackoff << uint(h.consecutiveFails-1)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	h.backoffUntil = time.Now().Add(backoff)
+}
+
+// statusCoder is implemented by HTTP errors that expose the response status code; RouterProvider
+// uses it to tell a bad API key (401/403, permanent) apart from rate limiting or a transient
+// server error (429/5xx, temporary backoff).
+type statusCoder interface {
+	StatusCode() int
+}
+
+// isCallerCancellation reports whether err is the caller's own ctx being cancelled or timing
+// out, as opposed to the backend itself failing.
+func isCallerCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func isAuthError(err error) bool {
+	var sc statusCoder
+	if ok := asStatusCoder(err, &sc); ok {
+		return sc.StatusCode() == 401 || sc.StatusCode() == 403
+	}
+	return false
+}
+
+func asStatusCoder(err error, target *statusCoder) bool {
+	sc, ok := err.(statusCoder)
+	if !ok {
+		return false
+	}
+	*target = sc
+	return true
+}
+
+// RouterProvider fans a single Provider interface out across several backends (e.g. Groq as a
+// fast/cheap primary with OpenAI as fallback), tracking their health and routing new requests
+// away from backends that are erroring or rate limited.
+type RouterProvider struct {
+	Policy RoutingPolicy
+
+	mu       sync.Mutex
+	backends []RouterBackend
+	health   []*health
+	next     int // round-robin cursor
+}
+
+// NewRouterProvider builds a RouterProvider over the given backends, tried in the order given
+// for RoutingPolicyPriority and as the candidate pool for every other policy.
+func NewRouterProvider(policy RoutingPolicy, backends ...RouterBackend) *RouterProvider {
+	r := &RouterProvider{Policy: policy, backends: backends}
+	for range backends {
+		r.health = append(r.health, &health{})
+	}
+	return r
+}
+
+// RecordPingResult lets callers feed Ping results into the same health tracking ChatStream
+// failures update, so a backend that's down is skipped even before a chat request is tried.
+func (r *RouterProvider) RecordPingResult(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, b := range r.backends {
+		if b.Name != name {
+			continue
+		}
+		if err != nil {
+			r.health[i].recordFailure(err)
+		} else {
+			r.health[i].recordSuccess(0)
+		}
+		return
+	}
+}
+
+func (r *RouterProvider) orderedCandidates() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	healthy := make([]int, 0, len(r.backends))
+	for i, h := range r.health {
+		if h.isHealthy() {
+			healthy = append(healthy, i)
+		}
+	}
+
+	switch r.Policy {
+	case RoutingPolicyRoundRobin:
+		if len(healthy) == 0 {
+			return healthy
+		}
+		start := r.next % len(healthy)
+		r.next++
+		return append(healthy[start:], healthy[:start]...)
+	case RoutingPolicyWeighted:
+		return r.weightedOrder(healthy)
+	case RoutingPolicyLeastLatency:
+		sortByLatency(healthy, r.health)
+		return healthy
+	default: // RoutingPolicyPriority
+		return healthy
+	}
+}
+
+func (r *RouterProvider) weightedOrder(healthy []int) []int {
+	// Highest weight first; backends with equal weight keep their configured order.
+	ordered := append([]int{}, healthy...)
+	for i := 1; i < len(ordered); i++ {
+		for j := i; j > 0 && r.backends[ordered[j]].weightOrDefault() > r.backends[ordered[j-1]].weightOrDefault(); j-- {
+			ordered[j], ordered[j-1] = ordered[j-1], ordered[j]
+		}
+	}
+	return ordered
+}
+
+func (b RouterBackend) weightOrDefault() int {
+	if b.Weight <= 0 {
+		return 1
+	}
+	return b.Weight
+}
+
+func sortByLatency(idx []int, healths []*health) {
+	for i := 1; i < len(idx); i++ {
+		for j := i; j > 0 && healths[idx[j]].latency < healths[idx[j-1]].latency; j-- {
+			idx[j], idx[j-1] = idx[j-1], idx[j]
+		}
+	}
+}
+
+// ChatStream picks a healthy backend and streams from it. If the stream errors before any
+// event is delivered, it's transparently retried against the next healthy backend; once a
+// backend has started delivering events, a later failure is surfaced to the caller instead of
+// silently failing over, since the caller may already have shown partial output.
+func (r *RouterProvider) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ChatStream, error) {
+	candidates := r.orderedCandidates()
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("ai: no healthy provider available")
+	}
+
+	var lastErr error
+	for _, i := range candidates {
+		backend := r.backends[i]
+		h := r.health[i]
+
+		start := time.Now()
+		stream, err := backend.Provider.ChatStream(ctx, model, conversations, options)
+		if err != nil {
+			h.recordFailure(err)
+			lastErr = err
+			continue
+		}
+
+		firstEvent, err := stream.ReceiveEvent(ctx)
+		if err != nil && err != io.EOF {
+			h.recordFailure(err)
+			lastErr = err
+			continue
+		}
+
+		h.recordSuccess(time.Since(start))
+		return &routedStream{
+			backend:   backend.Name,
+			health:    h,
+			inner:     stream,
+			primed:    &firstEvent,
+			primedErr: err,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("ai: all providers unavailable, last error: %w", lastErr)
+}
+
+// routedStream wraps the chosen backend's stream, replaying the already-received first event
+// (consumed by ChatStream to decide whether the backend actually started working) and recording
+// mid-stream failures against that backend's health without failing the request over.
+type routedStream struct {
+	backend   string
+	health    *health
+	inner     ChatStream
+	primed    *StreamEvent
+	primedErr error
+	replayed  bool
+}
+
+func (s *routedStream) ReceiveEvent(ctx context.Context) (StreamEvent, error) {
+	if !s.replayed {
+		s.replayed = true
+		return *s.primed, s.primedErr
+	}
+
+	event, err := s.inner.ReceiveEvent(ctx)
+	if err != nil && err != io.EOF {
+		s.health.recordFailure(err)
+		util.GetLogger().Warn(util.NewTraceContext(), fmt.Sprintf("ai: mid-stream error from %s: %s", s.backend, err))
+	}
+	return event, err
+}
+
+func (s *routedStream) Receive(ctx context.Context) (string, error) {
+	for {
+		event, err := s.ReceiveEvent(ctx)
+		if err != nil {
+			return "", err
+		}
+		if event.Type == StreamEventTypeTextDelta {
+			return event.TextDelta, nil
+		}
+	}
+}
+
+func (s *routedStream) SetReadDeadline(t time.Time) error {
+	return s.inner.SetReadDeadline(t)
+}
+
+// Models unions the models every backend exposes, tagging each with the backend's configured
+// Name so the UI can still target one specifically even when two backends share a Provider
+// kind (e.g. Groq configured as both priority and fallback).
+func (r *RouterProvider) Models(ctx context.Context) ([]common.Model, error) {
+	var models []common.Model
+	var lastErr error
+	for _, backend := range r.backends {
+		backendModels, err := backend.Provider.Models(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, m := range backendModels {
+			m.BackendName = backend.Name
+			models = append(models, m)
+		}
+	}
+	if len(models) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return models, nil
+}
+
+// Ping checks every backend and updates health accordingly; it returns an error only if every
+// backend is unreachable, since the point of the router is to keep working when some aren't.
+func (r *RouterProvider) Ping(ctx context.Context) error {
+	var lastErr error
+	healthyCount := 0
+	for i, backend := range r.backends {
+		err := backend.Provider.Ping(ctx)
+		if err != nil {
+			r.health[i].recordFailure(err)
+			lastErr = err
+			continue
+		}
+		r.health[i].recordSuccess(0)
+		healthyCount++
+	}
+	if healthyCount == 0 {
+		return fmt.Errorf("ai: no provider reachable, last error: %w", lastErr)
+	}
+	return nil
+}
+
+// Embed delegates to the first healthy backend that supports embeddings, trying the rest if one
+// reports ErrEmbeddingsNotSupported.
+func (r *RouterProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var lastErr error
+	for _, i := range r.orderedCandidates() {
+		vectors, err := r.backends[i].Provider.Embed(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrEmbeddingsNotSupported
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+	"wox/common"
+)
+
+type fakeProvider struct {
+	name       string
+	chatErr    error
+	models     []common.Model
+	pingErr    error
+	streamText string
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ChatStream, error) {
+	if f.chatErr != nil {
+		return nil, f.chatErr
+	}
+	return &fakeStream{text: f.streamText}, nil
+}
+
+func (f *fakeProvider) Models(ctx context.Context) ([]common.Model, error) {
+	return f.models, nil
+}
+
+func (f *fakeProvider) Ping(ctx context.Context) error {
+	return f.pingErr
+}
+
+func (f *fakeProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, ErrEmbeddingsNotSupported
+}
+
+type fakeStream struct {
+	text string
+	sent bool
+}
+
+func (s *fakeStream) ReceiveEvent(ctx context.Context) (StreamEvent, error) {
+	if s.sent {
+		return StreamEvent{}, io.EOF
+	}
+	s.sent = true
+	return StreamEvent{Type: StreamEventTypeTextDelta, TextDelta: s.text}, nil
+}
+
+func (s *fakeStream) Receive(ctx context.Context) (string, error) {
+	event, err := s.ReceiveEvent(ctx)
+	return event.TextDelta, err
+}
+
+func (s *fakeStream) SetReadDeadline(t time.Time) error { return nil }
+
+type statusError struct{ code int }
+
+func (e *statusError) Error() string   { return "status error" }
+func (e *statusError) StatusCode() int { return e.code }
+
+func TestRouterProviderFallsBackToNextHealthyBackend(t *testing.T) {
+	primary := &fakeProvider{name: "primary", chatErr: errors.New("boom")}
+	fallback := &fakeProvider{name: "fallback", streamText: "hi"}
+
+	router := NewRouterProvider(RoutingPolicyPriority,
+		RouterBackend{Name: "primary", Provider: primary},
+		RouterBackend{Name: "fallback", Provider: fallback},
+	)
+
+	stream, err := router.ChatStream(context.Background(), common.Model{}, nil, common.ChatOptions{})
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got error: %v", err)
+	}
+	text, err := stream.Receive(context.Background())
+	if err != nil || text != "hi" {
+		t.Fatalf("expected fallback's text, got %q, %v", text, err)
+	}
+}
+
+func TestHealthPermanentlyDownOnAuthError(t *testing.T) {
+	h := &health{}
+	h.recordFailure(&statusError{code: 401})
+
+	if !h.permanentlyDown {
+		t.Fatalf("expected a 401 to mark the backend permanently down")
+	}
+	if h.isHealthy() {
+		t.Fatalf("expected a permanently down backend to be unhealthy")
+	}
+}
+
+func TestHealthTemporaryBackoffOnServerError(t *testing.T) {
+	h := &health{}
+	h.recordFailure(&statusError{code: 503})
+
+	if h.permanentlyDown {
+		t.Fatalf("a 503 should back off, not permanently disable the backend")
+	}
+	if h.isHealthy() {
+		t.Fatalf("expected the backend to be unhealthy during its backoff window")
+	}
+}
+
+func TestHealthIgnoresCallerCancellation(t *testing.T) {
+	h := &health{}
+	h.recordFailure(context.Canceled)
+
+	if !h.isHealthy() {
+		t.Fatalf("a caller cancelling its own request should not count against backend health")
+	}
+}
+
+func TestRouterProviderModelsTagsBackendName(t *testing.T) {
+	a := &fakeProvider{models: []common.Model{{Name: "llama3", Provider: common.ProviderNameGroq}}}
+	b := &fakeProvider{models: []common.Model{{Name: "llama3", Provider: common.ProviderNameGroq}}}
+
+	router := NewRouterProvider(RoutingPolicyPriority,
+		RouterBackend{Name: "primary-groq", Provider: a},
+		RouterBackend{Name: "fallback-groq", Provider: b},
+	)
+
+	models, err := router.Models(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("expected 2 models, got %d", len(models))
+	}
+	if models[0].BackendName == models[1].BackendName {
+		t.Fatalf("expected distinct backend names, both got %q", models[0].BackendName)
+	}
+}
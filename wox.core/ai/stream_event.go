@@ -0,0 +1,62 @@
+package ai
+
+// StreamEventType discriminates the payload carried by a StreamEvent.
+type StreamEventType string
+
+const (
+	StreamEventTypeTextDelta     StreamEventType = "text_delta"
+	StreamEventTypeToolCallDelta StreamEventType = "tool_call_delta"
+	StreamEventTypeToolCall      StreamEventType = "tool_call"
+	StreamEventTypeUsage         StreamEventType = "usage"
+	StreamEventTypeFinish        StreamEventType = "finish"
+	// StreamEventTypeCitations is emitted by ai/chain's RetrievalQAChain ahead of the model's
+	// own events, carrying the IDs of the documents retrieved for the query.
+	StreamEventTypeCitations StreamEventType = "citations"
+)
+
+// StreamEvent is a single unit of streamed model output. Only the field matching Type is
+// populated; the rest are left at their zero value.
+type StreamEvent struct {
+	Type StreamEventType
+
+	TextDelta     string
+	ToolCallDelta ToolCallDelta
+	ToolCall      ToolCall
+	Usage         Usage
+	Finish        Finish
+	Citations     Citations
+}
+
+// ToolCallDelta is one incremental fragment of a tool call's arguments. OpenAI-compatible APIs
+// stream function-call arguments a few characters at a time, keyed by the tool call's index
+// within the response, so fragments must be reassembled in index order before the JSON is valid.
+type ToolCallDelta struct {
+	Index        int
+	ID           string
+	Name         string
+	ArgsFragment string
+}
+
+// ToolCall is a fully-assembled request from the model to invoke a tool.
+type ToolCall struct {
+	ID            string
+	Name          string
+	ArgumentsJSON string
+}
+
+// Usage reports token accounting for a completed request, used for billing and limit UI.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// Finish marks the end of a model turn and why it ended, e.g. "stop", "tool_calls" or "length".
+type Finish struct {
+	Reason string
+}
+
+// Citations lists the IDs of the documents a RetrievalQAChain grounded its answer in.
+type Citations struct {
+	DocumentIDs []string
+}
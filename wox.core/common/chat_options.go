@@ -0,0 +1,6 @@
+package common
+
+// ChatOptions carries the per-request knobs a Provider.ChatStream call accepts.
+type ChatOptions struct {
+	Tools []MCPTool
+}
@@ -0,0 +1,44 @@
+package common
+
+// ConversationRole identifies who produced a Conversation turn.
+type ConversationRole string
+
+const (
+	ConversationRoleUser   ConversationRole = "user"
+	ConversationRoleAI     ConversationRole = "ai"
+	ConversationRoleSystem ConversationRole = "system"
+	// ConversationRoleTool carries the result of an MCP tool invocation back to the model, so
+	// the agent loop in ai/agent can hand it the output of a tool call it asked for.
+	ConversationRoleTool ConversationRole = "tool"
+)
+
+// Conversation is a single message in a conversation DAG. ConversationID groups every message
+// belonging to the same chat session; ParentID is empty for that session's first message, and
+// messages sharing a ParentID are siblings - alternate edits of the same turn. Text is the
+// turn's content for every role; ToolCallID is only set on ConversationRoleTool turns, linking
+// the result back to the ToolCall that requested it. ToolCalls is only set on a
+// ConversationRoleAI turn that asked the model's caller to invoke one or more tools - a provider's
+// convertConversations must emit it ahead of the matching ConversationRoleTool turns, since an
+// OpenAI/Groq-compatible chat API rejects a "tool" message that isn't a response to a preceding
+// "tool_calls" message, even when the assistant turn otherwise has no text.
+//
+// Only one sibling under a given ParentID is "active" at a time - tracked by the
+// conversation.Store via its children/activeChild maps, not by the message itself.
+type Conversation struct {
+	ID             string
+	ConversationID string
+	ParentID       string
+	Role           ConversationRole
+	Text           string
+	ToolCallID     string
+	ToolCalls      []ToolCall
+}
+
+// ToolCall is a tool invocation the model requested on an assistant turn. It mirrors
+// ai.ToolCall, copied into common rather than referenced directly so common doesn't import ai
+// (which itself imports common for Conversation, Model, and ChatOptions).
+type ToolCall struct {
+	ID            string
+	Name          string
+	ArgumentsJSON string
+}
@@ -0,0 +1,7 @@
+package common
+
+// MCPTool describes a single tool exposed by an MCP server that a model can be offered to call.
+type MCPTool struct {
+	Name        string
+	Description string
+}
@@ -0,0 +1,19 @@
+package common
+
+// ProviderName identifies which backend a Model is served by.
+type ProviderName string
+
+const (
+	ProviderNameGroq ProviderName = "groq"
+	ProviderNameGRPC ProviderName = "grpc"
+)
+
+// Model identifies a single model a Provider can be asked to chat with. BackendName is only
+// set when the model came back from an ai.RouterProvider - it's the RouterBackend.Name the
+// model was reported by, so a UI listing models from several same-kind backends (e.g. two
+// "groq" backends configured as priority and fallback) can still tell them apart and target one.
+type Model struct {
+	Name        string
+	Provider    ProviderName
+	BackendName string
+}
@@ -0,0 +1,155 @@
+// Package conversation manages conversation DAGs: every edited message forks a new sibling
+// branch instead of overwriting history, and the Store tracks which branch is active at each
+// fork within each conversation so the provider layer always sees a plain linear transcript.
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"wox/ai"
+	"wox/common"
+	"wox/util"
+)
+
+// rootParentID is the synthetic ParentID used for a conversation's first message.
+const rootParentID = ""
+
+// fork identifies one point where a conversation's history can branch: a specific ParentID
+// within a specific conversation. Scoping by ConversationID as well as ParentID is what lets
+// many independent conversations - every one of them starting from ParentID "" - share a single
+// Store without clobbering each other's active branch.
+type fork struct {
+	conversationID string
+	parentID       string
+}
+
+// Store holds every conversation's message DAG in memory and tracks which branch is active at
+// each fork, so the TUI can show a "1/3 ▶" indicator on messages with siblings and the provider
+// layer always gets a single linear transcript.
+type Store struct {
+	mu          sync.Mutex
+	messages    map[string]common.Conversation // message ID -> message
+	children    map[fork][]string              // fork -> child message IDs, in creation order
+	activeChild map[fork]string                // fork -> the child currently on the active path
+}
+
+// NewStore returns an empty, in-memory conversation store.
+func NewStore() *Store {
+	return &Store{
+		messages:    make(map[string]common.Conversation),
+		children:    make(map[fork][]string),
+		activeChild: make(map[fork]string),
+	}
+}
+
+// AppendMessage adds a new message to conversationID as a child of parentID (rootParentID for
+// the session's first message) and makes it the active sibling at that fork point.
+func (s *Store) AppendMessage(conversationID string, parentID string, role common.ConversationRole, text string) common.Conversation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := common.Conversation{
+		ID:             util.NewId(),
+		ConversationID: conversationID,
+		ParentID:       parentID,
+		Role:           role,
+		Text:           text,
+	}
+
+	f := fork{conversationID: conversationID, parentID: parentID}
+	s.messages[msg.ID] = msg
+	s.children[f] = append(s.children[f], msg.ID)
+	s.activeChild[f] = msg.ID
+	return msg
+}
+
+// EditMessage forks a new sibling of id carrying newText, under the same parent and within the
+// same conversation, and makes it the active branch there. The original message and anything
+// descending from it are left untouched, so switching back to the old branch still works. It
+// returns the new message's ID, which SwitchBranch takes to make this branch active again later.
+func (s *Store) EditMessage(id string, newText string) (newBranchID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	original, ok := s.messages[id]
+	if !ok {
+		return "", fmt.Errorf("conversation: unknown message %q", id)
+	}
+
+	edited := common.Conversation{
+		ID:             util.NewId(),
+		ConversationID: original.ConversationID,
+		ParentID:       original.ParentID,
+		Role:           original.Role,
+		Text:           newText,
+	}
+
+	f := fork{conversationID: original.ConversationID, parentID: original.ParentID}
+	s.messages[edited.ID] = edited
+	s.children[f] = append(s.children[f], edited.ID)
+	s.activeChild[f] = edited.ID
+	return edited.ID, nil
+}
+
+// SwitchBranch makes branchID the active sibling at whichever fork point it belongs to within
+// conversationID, so ActivePath descends through it instead of whichever sibling was active
+// before.
+func (s *Store) SwitchBranch(conversationID string, branchID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	branch, ok := s.messages[branchID]
+	if !ok || branch.ConversationID != conversationID {
+		return fmt.Errorf("conversation: %q is not a branch of conversation %q", branchID, conversationID)
+	}
+
+	s.activeChild[fork{conversationID: conversationID, parentID: branch.ParentID}] = branchID
+	return nil
+}
+
+// ListBranches returns every sibling of messageID - the alternate edits available at that fork
+// point - in the order they were created, for the UI to render as "1/3 ▶".
+func (s *Store) ListBranches(messageID string) ([]common.Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, ok := s.messages[messageID]
+	if !ok {
+		return nil, fmt.Errorf("conversation: unknown message %q", messageID)
+	}
+
+	f := fork{conversationID: msg.ConversationID, parentID: msg.ParentID}
+	siblings := make([]common.Conversation, 0, len(s.children[f]))
+	for _, childID := range s.children[f] {
+		siblings = append(siblings, s.messages[childID])
+	}
+	return siblings, nil
+}
+
+// ActivePath linearizes conversationID's currently-selected branch, starting from its first
+// message and following the active sibling at every fork down to the current leaf. This is
+// what the provider layer should be handed instead of the whole DAG.
+func (s *Store) ActivePath(conversationID string) []common.Conversation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var path []common.Conversation
+	parentID := rootParentID
+	for {
+		childID, ok := s.activeChild[fork{conversationID: conversationID, parentID: parentID}]
+		if !ok {
+			return path
+		}
+		msg := s.messages[childID]
+		path = append(path, msg)
+		parentID = msg.ID
+	}
+}
+
+// ChatStream linearizes conversationID's active branch via ActivePath and delegates to
+// provider.ChatStream, so callers always send the model a single linear transcript without
+// having to remember to call ActivePath themselves.
+func (s *Store) ChatStream(ctx context.Context, provider ai.Provider, model common.Model, conversationID string, options common.ChatOptions) (ai.ChatStream, error) {
+	return provider.ChatStream(ctx, model, s.ActivePath(conversationID), options)
+}
@@ -0,0 +1,107 @@
+package conversation
+
+import (
+	"context"
+	"testing"
+	"wox/ai"
+	"wox/common"
+)
+
+type fakeProvider struct {
+	lastConversations []common.Conversation
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, model common.Model, conversations []common.Conversation, options common.ChatOptions) (ai.ChatStream, error) {
+	f.lastConversations = conversations
+	return nil, nil
+}
+
+func (f *fakeProvider) Models(ctx context.Context) ([]common.Model, error) { return nil, nil }
+func (f *fakeProvider) Ping(ctx context.Context) error                     { return nil }
+func (f *fakeProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, ai.ErrEmbeddingsNotSupported
+}
+
+func TestStoreKeepsIndependentConversationsSeparate(t *testing.T) {
+	store := NewStore()
+
+	first := store.AppendMessage("conv-1", rootParentID, common.ConversationRoleUser, "hello from conv 1")
+	second := store.AppendMessage("conv-2", rootParentID, common.ConversationRoleUser, "hello from conv 2")
+
+	path1 := store.ActivePath("conv-1")
+	path2 := store.ActivePath("conv-2")
+
+	if len(path1) != 1 || path1[0].ID != first.ID {
+		t.Fatalf("conv-1's active path should only contain its own first message, got %+v", path1)
+	}
+	if len(path2) != 1 || path2[0].ID != second.ID {
+		t.Fatalf("conv-2's active path should only contain its own first message, got %+v", path2)
+	}
+}
+
+func TestEditMessageForksWithoutAffectingOtherConversations(t *testing.T) {
+	store := NewStore()
+
+	msg := store.AppendMessage("conv-1", rootParentID, common.ConversationRoleUser, "original")
+	store.AppendMessage("conv-2", rootParentID, common.ConversationRoleUser, "untouched")
+
+	newBranchID, err := store.EditMessage(msg.ID, "edited")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path1 := store.ActivePath("conv-1")
+	if len(path1) != 1 || path1[0].ID != newBranchID || path1[0].Text != "edited" {
+		t.Fatalf("expected conv-1's active path to be the edited branch, got %+v", path1)
+	}
+
+	path2 := store.ActivePath("conv-2")
+	if len(path2) != 1 || path2[0].Text != "untouched" {
+		t.Fatalf("editing conv-1 should not affect conv-2, got %+v", path2)
+	}
+
+	branches, err := store.ListBranches(msg.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(branches) != 2 {
+		t.Fatalf("expected 2 sibling branches (original + edit), got %d", len(branches))
+	}
+}
+
+func TestSwitchBranchChangesActivePath(t *testing.T) {
+	store := NewStore()
+
+	original := store.AppendMessage("conv-1", rootParentID, common.ConversationRoleUser, "v1")
+	newBranchID, _ := store.EditMessage(original.ID, "v2")
+
+	if path := store.ActivePath("conv-1"); path[0].Text != "v2" {
+		t.Fatalf("expected the edit to be active, got %q", path[0].Text)
+	}
+
+	if err := store.SwitchBranch("conv-1", original.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path := store.ActivePath("conv-1"); path[0].Text != "v1" {
+		t.Fatalf("expected switching back to original, got %q", path[0].Text)
+	}
+
+	if err := store.SwitchBranch("conv-2", newBranchID); err == nil {
+		t.Fatalf("expected switching a branch under the wrong conversation ID to fail")
+	}
+}
+
+func TestStoreChatStreamSendsOnlyTheActivePath(t *testing.T) {
+	store := NewStore()
+	provider := &fakeProvider{}
+
+	store.AppendMessage("conv-1", rootParentID, common.ConversationRoleUser, "hi")
+
+	_, err := store.ChatStream(context.Background(), provider, common.Model{}, "conv-1", common.ChatOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(provider.lastConversations) != 1 || provider.lastConversations[0].Text != "hi" {
+		t.Fatalf("expected the provider to receive the linearized active path, got %+v", provider.lastConversations)
+	}
+}
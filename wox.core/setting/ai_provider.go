@@ -0,0 +1,18 @@
+package setting
+
+// AIProviderType identifies which kind of backend an AIProvider config connects to.
+type AIProviderType string
+
+const (
+	AIProviderTypeGroq AIProviderType = "groq"
+	// AIProviderTypeGRPC connects to an out-of-process provider plugin over gRPC, at Address.
+	AIProviderTypeGRPC AIProviderType = "grpc"
+)
+
+// AIProvider holds the user-configured connection details for a single AI backend.
+type AIProvider struct {
+	Type   AIProviderType
+	ApiKey string
+	// Address is only used by AIProviderTypeGRPC: a "unix:/path/to.sock" or "host:port" endpoint.
+	Address string
+}